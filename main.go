@@ -1,61 +1,118 @@
 package main
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
+	"github.com/robfig/cron/v3"
+	qrcode "github.com/skip2/go-qrcode"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	tele "gopkg.in/telebot.v3"
+
+	"github.com/thaimozhi-2005/GO_ROBOT/metrics"
 )
 
 // Database Models
 type Admin struct {
-	ID         uint      `gorm:"primaryKey"`
-	TelegramID int64     `gorm:"unique;not null"`
-	Username   string    `gorm:"type:varchar(100)"`
-	JoinedAt   time.Time `gorm:"autoCreateTime"`
+	ID          uint      `gorm:"primaryKey"`
+	TelegramID  int64     `gorm:"unique;not null"`
+	Username    string    `gorm:"type:varchar(100)"`
+	TOTPSecret  string    `gorm:"type:varchar(32)"`
+	TOTPEnabled bool      `gorm:"default:false"`
+	JoinedAt    time.Time `gorm:"autoCreateTime"`
 }
 
 type Bot struct {
 	ID              uint      `gorm:"primaryKey"`
 	BotUsername     string    `gorm:"type:varchar(100);not null"`
-	BotURL          string    `gorm:"type:varchar(500)"` // HTTP URL to ping
-	IntervalMinutes int       `gorm:"default:5"`
+	BotURL          string    `gorm:"type:varchar(500)"` // Check target: URL for http/json, host:port for tcp
+	CheckType       string    `gorm:"type:varchar(20);default:'http'"` // http, tcp, telegram, json
+	BotToken        string    `gorm:"type:varchar(200)"`               // Telegram bot token, used by the telegram check
+	SuccessExpr     string    `gorm:"type:varchar(200)"`               // JSONPath-style assertion, used by the json check
+	Schedule        string    `gorm:"type:varchar(100);default:'*/5 * * * *'"` // Cron expression controlling ping frequency
 	LastPing        time.Time
 	Status          string    `gorm:"type:varchar(20);default:'Unknown'"`
 	AddedBy         int64     `gorm:"not null"`
 	CreatedAt       time.Time `gorm:"autoCreateTime"`
 }
 
+// ConversationState tracks a user's progress through a multi-step chat
+// flow (e.g. the /addbot wizard) so free-text replies can be routed to the
+// right step instead of being ignored.
+type ConversationState struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    int64     `gorm:"unique;not null"`
+	Command   string    `gorm:"type:varchar(50);not null"`
+	Step      int       `gorm:"not null"`
+	Payload   string    `gorm:"type:text"` // JSON-encoded partial command state
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
 type UptimeLog struct {
 	ID        uint      `gorm:"primaryKey"`
 	BotID     uint      `gorm:"not null"`
 	Timestamp time.Time `gorm:"autoCreateTime"`
 	Result    bool      `gorm:"not null"`
+	LatencyMs int64     `gorm:"default:0"`
+	Error     string    `gorm:"type:varchar(500)"`
 }
 
 var (
-	db        *gorm.DB
-	bot       *tele.Bot
-	adminIDs  map[int64]bool
+	db       *gorm.DB
+	bot      *tele.Bot
+	adminIDs map[int64]bool
+)
+
+// authSessionTTL is how long a /auth or /verify2fa session stays valid
+// before destructive commands require re-authentication.
+const authSessionTTL = 5 * time.Minute
+
+var (
+	authSessions   = make(map[int64]time.Time)
+	authSessionsMu sync.Mutex
+)
+
+var (
+	cronScheduler *cron.Cron
+	cronEntries   = make(map[uint]cron.EntryID)
+	cronEntriesMu sync.Mutex
 )
 
 func main() {
 	// Initialize database
 	initDB()
 
+	// Create the cron scheduler synchronously so it's ready before any
+	// command can reach scheduleBot; initBot starts long-polling in a
+	// goroutine and commands can arrive as soon as that goroutine runs.
+	cronScheduler = cron.New()
+
 	// Initialize Telegram bot
 	initBot()
 
-	// Start keep-alive scheduler
+	// Load existing bots into the scheduler and start it
 	go startScheduler()
 
+	// Sweep expired conversation states (e.g. abandoned /addbot wizards)
+	go sweepExpiredConversations()
+
 	// Start HTTP server for Render
 	startHTTPServer()
 }
@@ -74,7 +131,7 @@ func initDB() {
 	}
 
 	// Auto-migrate schemas
-	db.AutoMigrate(&Admin{}, &Bot{}, &UptimeLog{})
+	db.AutoMigrate(&Admin{}, &Bot{}, &UptimeLog{}, &ConversationState{})
 	log.Println("✅ Database connected and migrated")
 
 	// Load admin IDs from database
@@ -133,8 +190,19 @@ func initBot() {
 	bot.Handle("/addbot", handleAddBot)
 	bot.Handle("/removebot", handleRemoveBot)
 	bot.Handle("/listbots", handleListBots)
+	bot.Handle("/findbot", handleFindBot)
 	bot.Handle("/stats", handleStats)
 	bot.Handle("/addadmin", handleAddAdmin)
+	bot.Handle("/enable2fa", handleEnable2FA)
+	bot.Handle("/verify2fa", handleVerify2FA)
+	bot.Handle("/auth", handleAuth)
+	bot.Handle("/schedule", handleSchedule)
+	bot.Handle("/cancel", handleCancel)
+
+	// Route free-text replies and inline button presses to whichever
+	// multi-step flow (e.g. the /addbot wizard) the sender currently has open
+	bot.Handle(tele.OnText, handleConversationText)
+	bot.Handle(tele.OnCallback, handleWizardCallback)
 
 	log.Println("✅ Telegram bot initialized")
 
@@ -147,6 +215,33 @@ func isAdmin(userID int64) bool {
 	return adminIDs[userID]
 }
 
+// Middleware: Check if user holds a fresh authenticated session. Admins who
+// never enabled 2FA are treated as authenticated (2FA is opt-in); admins who
+// enabled it must have run /auth or /verify2fa within the last 5 minutes.
+func isAuthenticated(userID int64) bool {
+	var admin Admin
+	if err := db.Where("telegram_id = ?", userID).First(&admin).Error; err != nil || !admin.TOTPEnabled {
+		return true
+	}
+
+	authSessionsMu.Lock()
+	defer authSessionsMu.Unlock()
+
+	expiry, ok := authSessions[userID]
+	if !ok || time.Now().After(expiry) {
+		delete(authSessions, userID)
+		return false
+	}
+	return true
+}
+
+// markAuthenticated opens a 5-minute authenticated session for userID.
+func markAuthenticated(userID int64) {
+	authSessionsMu.Lock()
+	defer authSessionsMu.Unlock()
+	authSessions[userID] = time.Now().Add(authSessionTTL)
+}
+
 // Handler: /start
 func handleStart(c tele.Context) error {
 	if !isAdmin(c.Sender().ID) {
@@ -170,18 +265,36 @@ func handleHelp(c tele.Context) error {
 
 	helpText := `📖 Available Commands:
 
-/addbot <username> <url> <interval> - Add bot to monitor
-   Example: /addbot @mybot https://mybot.onrender.com 5
+/addbot - Start an interactive wizard to add a bot to monitor
+   Send /cancel anytime to abort the wizard
+
+/addbot <username> <type> <target> <schedule> [expr] - Add bot in one line
+   Types: http, tcp, telegram, json
+   Schedule is a cron expression with underscores instead of spaces
+   Example: /addbot @mybot http https://mybot.onrender.com */5_*_*_*_*
+   Example: /addbot @mybot tcp myhost.com:5432 @every_30s
+   Example: /addbot @mybot telegram 123456:ABC-token 0_9-17_*_*_1-5
+   Example: /addbot @mybot json https://mybot.onrender.com/health */5_*_*_*_* $.status == "ok"
+
+/schedule <username> <cron_expr> - Change a bot's schedule at runtime
+   Example: /schedule @mybot 0_9-17_*_*_1-5
 
 /removebot <username> - Remove bot from monitoring
    Example: /removebot @mybot
 
-/listbots - Show all monitored bots
+/listbots - Show all monitored bots (includes next scheduled run)
+
+/findbot <query> - Fuzzy-search monitored bots by name or URL
+   Example: /findbot myrbot
 
 /stats - View uptime statistics
 
 /addadmin <user_id> - Add new admin (super admin only)
 
+/enable2fa - Enroll in TOTP two-factor authentication
+/verify2fa <code> - Confirm 2FA enrollment with a code from your app
+/auth <code> - Open a 5-minute authenticated session for destructive commands
+
 /help - Show this help message`
 
 	return c.Send(helpText)
@@ -194,20 +307,48 @@ func handleAddBot(c tele.Context) error {
 	}
 
 	args := strings.Fields(c.Text())
-	if len(args) < 4 {
-		return c.Send("❌ Usage: /addbot <username> <url> <interval_minutes>\nExample: /addbot @mybot https://mybot.onrender.com 5")
+	if len(args) < 2 {
+		return startAddBotWizard(c)
+	}
+	if len(args) < 5 {
+		return c.Send("❌ Usage: /addbot <username> <type> <target> <schedule> [expr]\n" +
+			"Types: http, tcp, telegram, json\n" +
+			"Schedule is a cron expression with underscores instead of spaces\n" +
+			"Example: /addbot @mybot http https://mybot.onrender.com */5_*_*_*_*\n\n" +
+			"Or send /addbot with no arguments to use the step-by-step wizard.")
 	}
 
 	username := strings.TrimPrefix(args[1], "@")
-	botURL := args[2]
-	interval, err := strconv.Atoi(args[3])
-	if err != nil || interval < 1 {
-		return c.Send("❌ Invalid interval. Must be a positive number.")
+	checkType := strings.ToLower(args[2])
+	target := args[3]
+	schedule := normalizeSchedule(args[4])
+	if err := validateSchedule(schedule); err != nil {
+		return c.Send(fmt.Sprintf("❌ Invalid cron expression: %v", err))
 	}
 
-	// Validate URL
-	if !strings.HasPrefix(botURL, "http://") && !strings.HasPrefix(botURL, "https://") {
-		return c.Send("❌ Invalid URL. Must start with http:// or https://")
+	switch checkType {
+	case "http", "tcp", "telegram", "json":
+	default:
+		return c.Send("❌ Invalid check type. Must be one of: http, tcp, telegram, json.")
+	}
+
+	var successExpr string
+	if checkType == "json" {
+		if len(args) < 6 {
+			return c.Send("❌ json checks require an expression.\nExample: /addbot @mybot json https://mybot.onrender.com/health */5_*_*_*_* $.status == \"ok\"")
+		}
+		successExpr = strings.Join(args[5:], " ")
+	}
+
+	var botURL, botToken string
+	if checkType == "telegram" {
+		botToken = target
+	} else {
+		botURL = target
+		if (checkType == "http" || checkType == "json") &&
+			!strings.HasPrefix(botURL, "http://") && !strings.HasPrefix(botURL, "https://") {
+			return c.Send("❌ Invalid URL. Must start with http:// or https://")
+		}
 	}
 
 	// Check if bot already exists
@@ -219,17 +360,390 @@ func handleAddBot(c tele.Context) error {
 
 	// Add new bot
 	newBot := Bot{
-		BotUsername:     username,
-		BotURL:          botURL,
-		IntervalMinutes: interval,
-		Status:          "Unknown",
-		AddedBy:         c.Sender().ID,
-		LastPing:        time.Now(),
+		BotUsername: username,
+		BotURL:      botURL,
+		BotToken:    botToken,
+		CheckType:   checkType,
+		SuccessExpr: successExpr,
+		Schedule:    schedule,
+		Status:      "Unknown",
+		AddedBy:     c.Sender().ID,
+		LastPing:    time.Now(),
 	}
 
 	db.Create(&newBot)
+	scheduleBot(newBot)
 
-	return c.Send(fmt.Sprintf("✅ Bot @%s added successfully!\nURL: %s\nPing interval: %d minutes", username, botURL, interval))
+	return c.Send(fmt.Sprintf("✅ Bot @%s added successfully!\nType: %s\nTarget: %s\nSchedule: %s", username, checkType, target, schedule))
+}
+
+// Handler: /schedule
+func handleSchedule(c tele.Context) error {
+	if !isAdmin(c.Sender().ID) {
+		return c.Send("❌ Unauthorized.")
+	}
+
+	args := strings.Fields(c.Text())
+	if len(args) < 3 {
+		return c.Send("❌ Usage: /schedule <username> <cron_expr>\nExample: /schedule @mybot 0_9-17_*_*_1-5")
+	}
+
+	username := strings.TrimPrefix(args[1], "@")
+	schedule := normalizeSchedule(strings.Join(args[2:], " "))
+	if err := validateSchedule(schedule); err != nil {
+		return c.Send(fmt.Sprintf("❌ Invalid cron expression: %v", err))
+	}
+
+	var existingBot Bot
+	if err := db.Where("bot_username = ?", username).First(&existingBot).Error; err != nil {
+		return c.Send("❌ Bot not found in monitoring list.")
+	}
+
+	existingBot.Schedule = schedule
+	db.Save(&existingBot)
+	scheduleBot(existingBot)
+
+	return c.Send(fmt.Sprintf("✅ @%s rescheduled to `%s`.", username, schedule))
+}
+
+// Wizard steps for the /addbot conversation flow. Check type is collected
+// right after the username, before the target, so the target prompt and
+// its validation can be tailored per type (URL for http/json, host:port
+// for tcp, bot token for telegram).
+const (
+	stepUsername = iota + 1
+	stepCheckType
+	stepTarget
+	stepSchedule
+	stepScheduleCustom
+	stepJSONExpr
+)
+
+// addBotWizardPayload is the partial Bot data accumulated across the
+// /addbot wizard's steps, JSON-encoded into ConversationState.Payload.
+type addBotWizardPayload struct {
+	Username    string `json:"username,omitempty"`
+	CheckType   string `json:"check_type,omitempty"`
+	Target      string `json:"target,omitempty"`
+	Schedule    string `json:"schedule,omitempty"`
+	SuccessExpr string `json:"success_expr,omitempty"`
+}
+
+// Handler: /cancel
+func handleCancel(c tele.Context) error {
+	if !isAdmin(c.Sender().ID) {
+		return c.Send("❌ Unauthorized.")
+	}
+
+	db.Where("user_id = ?", c.Sender().ID).Delete(&ConversationState{})
+	return c.Send("❌ Cancelled.")
+}
+
+// Handler: routes a free-text reply to whichever step the sender's active
+// conversation is on. Silently does nothing if they have no open flow.
+func handleConversationText(c tele.Context) error {
+	var state ConversationState
+	if err := db.Where("user_id = ?", c.Sender().ID).First(&state).Error; err != nil {
+		return nil
+	}
+	if time.Now().After(state.ExpiresAt) {
+		db.Delete(&state)
+		return c.Send("⌛ Your session expired. Start again with /addbot.")
+	}
+
+	switch state.Command {
+	case "addbot":
+		return handleAddBotWizardText(c, state)
+	}
+	return nil
+}
+
+// Handler: routes an inline keyboard press to whichever step the sender's
+// active conversation is on.
+func handleWizardCallback(c tele.Context) error {
+	cb := c.Callback()
+	if cb == nil {
+		return nil
+	}
+
+	var state ConversationState
+	if err := db.Where("user_id = ?", c.Sender().ID).First(&state).Error; err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "This flow has expired."})
+	}
+	if time.Now().After(state.ExpiresAt) {
+		db.Delete(&state)
+		return c.Respond(&tele.CallbackResponse{Text: "This flow has expired."})
+	}
+
+	var payload addBotWizardPayload
+	json.Unmarshal([]byte(state.Payload), &payload)
+
+	// Buttons from markup.Data arrive here as "\f<unique>|<data>"; telebot
+	// only splits that apart for handlers registered under the unique ID.
+	unique, data := cb.Unique, cb.Data
+	if unique == "" && strings.HasPrefix(data, "\f") {
+		parts := strings.SplitN(data[1:], "|", 2)
+		unique, data = parts[0], ""
+		if len(parts) == 2 {
+			data = parts[1]
+		}
+	}
+
+	switch unique {
+	case "wizard_check":
+		checkType := data
+		if checkType == "skip" {
+			checkType = "http"
+		}
+		payload.CheckType = checkType
+		advanceWizard(&state, stepTarget, payload)
+		if err := c.Edit(targetPrompt(checkType)); err != nil {
+			return err
+		}
+		return c.Respond()
+
+	case "wizard_sched":
+		if data == "custom" {
+			advanceWizard(&state, stepScheduleCustom, payload)
+			if err := c.Edit("Step 4/5: Send a custom cron expression (underscores instead of spaces), e.g. `*/10_*_*_*_*`"); err != nil {
+				return err
+			}
+			return c.Respond()
+		}
+		payload.Schedule = data
+		if payload.CheckType == "json" {
+			advanceWizard(&state, stepJSONExpr, payload)
+			if err := c.Edit("Step 5/5: Send the JSON assertion expression, e.g. `$.status == \"ok\"`"); err != nil {
+				return err
+			}
+			return c.Respond()
+		}
+		if err := c.Edit(finishAddBotWizard(&state, payload, c.Sender().ID)); err != nil {
+			return err
+		}
+		return c.Respond()
+
+	case "wizard_back":
+		return wizardBack(c, &state, payload)
+
+	case "wizard_cancel":
+		db.Delete(&state)
+		if err := c.Edit("❌ Cancelled."); err != nil {
+			return err
+		}
+		return c.Respond()
+	}
+
+	return c.Respond()
+}
+
+// startAddBotWizard begins (or restarts) the interactive /addbot flow for
+// the sender.
+func startAddBotWizard(c tele.Context) error {
+	db.Where("user_id = ?", c.Sender().ID).Delete(&ConversationState{})
+
+	state := ConversationState{
+		UserID:    c.Sender().ID,
+		Command:   "addbot",
+		Step:      stepUsername,
+		Payload:   "{}",
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}
+	db.Create(&state)
+
+	return c.Send("🧙 Let's add a bot to monitor.\n\nStep 1/5: What's the bot's @username?\n\nSend /cancel anytime to abort.")
+}
+
+// handleAddBotWizardText advances the /addbot wizard in response to a
+// free-text reply.
+func handleAddBotWizardText(c tele.Context, state ConversationState) error {
+	var payload addBotWizardPayload
+	json.Unmarshal([]byte(state.Payload), &payload)
+
+	text := strings.TrimSpace(c.Text())
+
+	switch state.Step {
+	case stepUsername:
+		payload.Username = strings.TrimPrefix(text, "@")
+		advanceWizard(&state, stepCheckType, payload)
+		return c.Send("Step 2/5: Pick a check type.", checkTypeKeyboard())
+
+	case stepTarget:
+		if err := validateWizardTarget(payload.CheckType, text); err != nil {
+			return c.Send(fmt.Sprintf("❌ %v Try again, or /cancel.", err))
+		}
+		payload.Target = text
+		advanceWizard(&state, stepSchedule, payload)
+		return c.Send("Step 4/5: How often should I ping it?", scheduleKeyboard())
+
+	case stepScheduleCustom:
+		schedule := normalizeSchedule(text)
+		if err := validateSchedule(schedule); err != nil {
+			return c.Send(fmt.Sprintf("❌ Invalid cron expression: %v\nTry again, or /cancel.", err))
+		}
+		payload.Schedule = schedule
+		if payload.CheckType == "json" {
+			advanceWizard(&state, stepJSONExpr, payload)
+			return c.Send("Step 5/5: Send the JSON assertion expression, e.g. `$.status == \"ok\"`")
+		}
+		return c.Send(finishAddBotWizard(&state, payload, c.Sender().ID))
+
+	case stepJSONExpr:
+		payload.SuccessExpr = text
+		return c.Send(finishAddBotWizard(&state, payload, c.Sender().ID))
+
+	default:
+		return nil
+	}
+}
+
+// wizardBack moves the conversation back one step and re-sends that step's
+// prompt, preserving whatever payload has been collected so far. Only
+// reachable from steps whose keyboard has a Back button (check type and
+// schedule), so those are the only two cases that matter.
+func wizardBack(c tele.Context, state *ConversationState, payload addBotWizardPayload) error {
+	var msg string
+	switch state.Step {
+	case stepSchedule:
+		advanceWizard(state, stepTarget, payload)
+		msg = targetPrompt(payload.CheckType)
+	default:
+		advanceWizard(state, stepUsername, payload)
+		msg = "Step 1/5: What's the bot's @username?"
+	}
+
+	if err := c.Edit(msg); err != nil {
+		return err
+	}
+	return c.Respond()
+}
+
+// targetPrompt returns the step-3 prompt for whichever check type was
+// picked, since each type expects a differently shaped target.
+func targetPrompt(checkType string) string {
+	switch checkType {
+	case "tcp":
+		return "Step 3/5: What's the target? (host:port)"
+	case "telegram":
+		return "Step 3/5: What's the bot token?"
+	default:
+		return "Step 3/5: What URL should I ping?\n(must start with http:// or https://)"
+	}
+}
+
+// validateWizardTarget checks a wizard-entered target against the rules
+// for its check type, mirroring the one-line /addbot parser.
+func validateWizardTarget(checkType, target string) error {
+	switch checkType {
+	case "http", "json":
+		if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+			return fmt.Errorf("invalid URL. Must start with http:// or https://.")
+		}
+	case "tcp":
+		if !strings.Contains(target, ":") {
+			return fmt.Errorf("invalid target. Expected host:port.")
+		}
+	case "telegram":
+		if target == "" {
+			return fmt.Errorf("bot token cannot be empty.")
+		}
+	}
+	return nil
+}
+
+// finishAddBotWizard creates the Bot from the collected payload, schedules
+// it, clears the conversation state, and returns the message to show the
+// user (a confirmation, or an error if the username is already taken).
+func finishAddBotWizard(state *ConversationState, payload addBotWizardPayload, addedBy int64) string {
+	var existingBot Bot
+	if result := db.Where("bot_username = ?", payload.Username).First(&existingBot); result.RowsAffected > 0 {
+		db.Delete(state)
+		return "❌ Bot already exists in monitoring list."
+	}
+
+	var botURL, botToken string
+	if payload.CheckType == "telegram" {
+		botToken = payload.Target
+	} else {
+		botURL = payload.Target
+	}
+
+	newBot := Bot{
+		BotUsername: payload.Username,
+		BotURL:      botURL,
+		BotToken:    botToken,
+		CheckType:   payload.CheckType,
+		SuccessExpr: payload.SuccessExpr,
+		Schedule:    payload.Schedule,
+		Status:      "Unknown",
+		AddedBy:     addedBy,
+		LastPing:    time.Now(),
+	}
+	db.Create(&newBot)
+	scheduleBot(newBot)
+
+	db.Delete(state)
+
+	return fmt.Sprintf("✅ Bot @%s added successfully!\nType: %s\nTarget: %s\nSchedule: %s",
+		payload.Username, newBot.CheckType, payload.Target, newBot.Schedule)
+}
+
+// advanceWizard persists the next step and payload for an in-progress
+// conversation and refreshes its expiry.
+func advanceWizard(state *ConversationState, step int, payload addBotWizardPayload) {
+	raw, _ := json.Marshal(payload)
+	state.Step = step
+	state.Payload = string(raw)
+	state.ExpiresAt = time.Now().Add(10 * time.Minute)
+	db.Save(state)
+}
+
+// scheduleKeyboard offers the common ping frequencies plus a custom option.
+func scheduleKeyboard() *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+	btn1 := markup.Data("Every 1 min", "wizard_sched", "* * * * *")
+	btn5 := markup.Data("Every 5 min", "wizard_sched", "*/5 * * * *")
+	btn15 := markup.Data("Every 15 min", "wizard_sched", "*/15 * * * *")
+	btn60 := markup.Data("Every 60 min", "wizard_sched", "0 * * * *")
+	btnCustom := markup.Data("Custom…", "wizard_sched", "custom")
+	btnBack := markup.Data("⬅ Back", "wizard_back", "")
+	markup.Inline(
+		markup.Row(btn1, btn5),
+		markup.Row(btn15, btn60),
+		markup.Row(btnCustom),
+		markup.Row(btnBack),
+	)
+	return markup
+}
+
+// checkTypeKeyboard offers the supported Checker types plus a skip option
+// that defaults to http.
+func checkTypeKeyboard() *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+	btnHTTP := markup.Data("HTTP", "wizard_check", "http")
+	btnTCP := markup.Data("TCP", "wizard_check", "tcp")
+	btnTelegram := markup.Data("Telegram getMe", "wizard_check", "telegram")
+	btnJSON := markup.Data("JSON assertion", "wizard_check", "json")
+	btnSkip := markup.Data("Skip (use HTTP)", "wizard_check", "skip")
+	btnBack := markup.Data("⬅ Back", "wizard_back", "")
+	markup.Inline(
+		markup.Row(btnHTTP, btnTCP),
+		markup.Row(btnTelegram, btnJSON),
+		markup.Row(btnSkip),
+		markup.Row(btnBack),
+	)
+	return markup
+}
+
+// sweepExpiredConversations periodically deletes stale conversation states
+// so abandoned wizards don't linger forever.
+func sweepExpiredConversations() {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		db.Where("expires_at < ?", time.Now()).Delete(&ConversationState{})
+	}
 }
 
 // Handler: /removebot
@@ -237,6 +751,9 @@ func handleRemoveBot(c tele.Context) error {
 	if !isAdmin(c.Sender().ID) {
 		return c.Send("❌ Unauthorized.")
 	}
+	if !isAuthenticated(c.Sender().ID) {
+		return c.Send("🔒 This command requires a fresh 2FA session. Use /auth <code> first.")
+	}
 
 	args := strings.Fields(c.Text())
 	if len(args) < 2 {
@@ -245,11 +762,14 @@ func handleRemoveBot(c tele.Context) error {
 
 	username := strings.TrimPrefix(args[1], "@")
 
-	result := db.Where("bot_username = ?", username).Delete(&Bot{})
-	if result.RowsAffected == 0 {
+	var existingBot Bot
+	if err := db.Where("bot_username = ?", username).First(&existingBot).Error; err != nil {
 		return c.Send("❌ Bot not found in monitoring list.")
 	}
 
+	db.Delete(&existingBot)
+	unscheduleBot(existingBot.ID)
+
 	return c.Send(fmt.Sprintf("✅ Bot @%s removed from monitoring.", username))
 }
 
@@ -275,14 +795,184 @@ func handleListBots(c tele.Context) error {
 			statusEmoji = "❌"
 		}
 
-		message += fmt.Sprintf("%d. @%s %s\n   URL: %s\n   Interval: %d min | Last Ping: %s\n\n",
-			i+1, b.BotUsername, statusEmoji, b.BotURL, b.IntervalMinutes,
+		nextRunText := "n/a"
+		if next := nextRun(b.ID); !next.IsZero() {
+			nextRunText = next.Format("02 Jan 15:04")
+		}
+
+		message += fmt.Sprintf("%d. @%s %s [%s]\n   Target: %s\n   Schedule: %s | Next run: %s\n   Last Ping: %s\n\n",
+			i+1, b.BotUsername, statusEmoji, b.CheckType, checkTarget(b), b.Schedule, nextRunText,
 			b.LastPing.Format("02 Jan 15:04"))
 	}
 
 	return c.Send(message)
 }
 
+// Handler: /findbot
+func handleFindBot(c tele.Context) error {
+	if !isAdmin(c.Sender().ID) {
+		return c.Send("❌ Unauthorized.")
+	}
+
+	args := strings.Fields(c.Text())
+	if len(args) < 2 {
+		return c.Send("❌ Usage: /findbot <query>\nExample: /findbot myrbot")
+	}
+	query := strings.Join(args[1:], " ")
+
+	var bots []Bot
+	db.Find(&bots)
+
+	type fuzzyMatch struct {
+		bot     Bot
+		score   int
+		indices []int
+		field   string
+	}
+
+	const maxResults = 5
+
+	var matches []fuzzyMatch
+	for _, b := range bots {
+		var found bool
+		var bestScore int
+		var bestIndices []int
+		var bestField string
+
+		if score, idx, ok := fuzzyScore(query, b.BotUsername); ok && (!found || score > bestScore) {
+			found, bestScore, bestIndices, bestField = true, score, idx, b.BotUsername
+		}
+		if score, idx, ok := fuzzyScore(query, b.BotURL); ok && (!found || score > bestScore) {
+			found, bestScore, bestIndices, bestField = true, score, idx, b.BotURL
+		}
+
+		if found {
+			matches = append(matches, fuzzyMatch{bot: b, score: bestScore, indices: bestIndices, field: bestField})
+		}
+	}
+
+	if len(matches) == 0 {
+		return c.Send(fmt.Sprintf("🔍 No bots matching \"%s\" found.", query))
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	if len(matches) > maxResults {
+		matches = matches[:maxResults]
+	}
+
+	message := fmt.Sprintf("🔍 Matches for *%s*:\n\n", escapeMarkdownV2(query))
+	for i, m := range matches {
+		statusEmoji := "❓"
+		if m.bot.Status == "Online" {
+			statusEmoji = "✅"
+		} else if m.bot.Status == "Offline" {
+			statusEmoji = "❌"
+		}
+
+		message += fmt.Sprintf("%d\\. %s %s\n   Last ping: %s\n\n",
+			i+1, statusEmoji, highlightMatch(m.field, m.indices),
+			escapeMarkdownV2(m.bot.LastPing.Format("02 Jan 15:04")))
+	}
+
+	return c.Send(message, tele.ModeMarkdownV2)
+}
+
+// fuzzyScore implements a Bitap/Smith-Waterman style fuzzy match: pattern
+// characters must occur in text in order, consecutive runs and matches at a
+// word boundary (after -, _, ., / or a camelCase transition) score bonus
+// points, and skipped characters cost a small penalty. Returns ok=false if
+// not every pattern character could be matched in order.
+func fuzzyScore(pattern, text string) (score int, indices []int, ok bool) {
+	p := strings.ToLower(pattern)
+	t := strings.ToLower(text)
+
+	const (
+		consecutiveBonus = 15
+		boundaryBonus    = 10
+		gapPenalty       = 2
+	)
+
+	pi, ti := 0, 0
+	prevMatched := false
+	for pi < len(p) && ti < len(t) {
+		if p[pi] == t[ti] {
+			score++
+			if prevMatched {
+				score += consecutiveBonus
+			}
+			if isWordBoundary(text, ti) {
+				score += boundaryBonus
+			}
+			indices = append(indices, ti)
+			prevMatched = true
+			pi++
+			ti++
+		} else {
+			score -= gapPenalty
+			prevMatched = false
+			ti++
+		}
+	}
+
+	if pi < len(p) {
+		return 0, nil, false
+	}
+	return score, indices, true
+}
+
+// isWordBoundary reports whether the byte at index i starts a new "word" in
+// text: the very first character, the character after a -, _, . or /
+// separator, or an uppercase letter following a lowercase one.
+func isWordBoundary(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := rune(text[i-1])
+	cur := rune(text[i])
+	switch prev {
+	case '-', '_', '.', '/':
+		return true
+	}
+	return unicode.IsUpper(cur) && unicode.IsLower(prev)
+}
+
+// markdownV2Escaper escapes Telegram MarkdownV2 reserved characters.
+var markdownV2Escaper = strings.NewReplacer(
+	"_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(", "\\(", ")", "\\)",
+	"~", "\\~", "`", "\\`", ">", "\\>", "#", "\\#", "+", "\\+", "-", "\\-",
+	"=", "\\=", "|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
+)
+
+func escapeMarkdownV2(s string) string {
+	return markdownV2Escaper.Replace(s)
+}
+
+// highlightMatch renders text for MarkdownV2 with the runes at the given
+// indices wrapped in bold markers, escaping any reserved characters.
+func highlightMatch(text string, indices []int) string {
+	matched := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		matched[i] = true
+	}
+
+	var b strings.Builder
+	open := false
+	for i, r := range text {
+		if matched[i] && !open {
+			b.WriteString("*")
+			open = true
+		} else if !matched[i] && open {
+			b.WriteString("*")
+			open = false
+		}
+		b.WriteString(escapeMarkdownV2(string(r)))
+	}
+	if open {
+		b.WriteString("*")
+	}
+	return b.String()
+}
+
 // Handler: /stats
 func handleStats(c tele.Context) error {
 	if !isAdmin(c.Sender().ID) {
@@ -308,20 +998,43 @@ func handleStats(c tele.Context) error {
 			uptime = (float64(successLogs) / float64(totalLogs)) * 100
 		}
 
-		message += fmt.Sprintf("@%s\n", b.BotUsername)
+		var latencies []int64
+		db.Model(&UptimeLog{}).Where("bot_id = ? AND result = ?", b.ID, true).
+			Order("latency_ms").Pluck("latency_ms", &latencies)
+		p50 := percentile(latencies, 50)
+		p95 := percentile(latencies, 95)
+
+		message += fmt.Sprintf("@%s [%s]\n", b.BotUsername, b.CheckType)
 		message += fmt.Sprintf("  Status: %s\n", b.Status)
 		message += fmt.Sprintf("  Uptime: %.2f%%\n", uptime)
-		message += fmt.Sprintf("  Total Pings: %d\n\n", totalLogs)
+		message += fmt.Sprintf("  Total Pings: %d\n", totalLogs)
+		message += fmt.Sprintf("  Latency p50/p95: %dms / %dms\n\n", p50, p95)
 	}
 
 	return c.Send(message)
 }
 
+// percentile returns the p-th percentile (0-100) of an ascending-sorted
+// slice of latencies in milliseconds, or 0 if the slice is empty.
+func percentile(sorted []int64, p int) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := (p * len(sorted)) / 100
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
 // Handler: /addadmin
 func handleAddAdmin(c tele.Context) error {
 	if !isAdmin(c.Sender().ID) {
 		return c.Send("❌ Unauthorized.")
 	}
+	if !isAuthenticated(c.Sender().ID) {
+		return c.Send("🔒 This command requires a fresh 2FA session. Use /auth <code> first.")
+	}
 
 	args := strings.Fields(c.Text())
 	if len(args) < 2 {
@@ -347,52 +1060,418 @@ func handleAddAdmin(c tele.Context) error {
 	return c.Send(fmt.Sprintf("✅ Admin %d added successfully!", newAdminID))
 }
 
-// Scheduler: Send keep-alive pings
+// Handler: /enable2fa
+func handleEnable2FA(c tele.Context) error {
+	if !isAdmin(c.Sender().ID) {
+		return c.Send("❌ Unauthorized.")
+	}
+
+	var admin Admin
+	if err := db.Where("telegram_id = ?", c.Sender().ID).First(&admin).Error; err != nil {
+		return c.Send("❌ Admin record not found.")
+	}
+	if admin.TOTPEnabled {
+		return c.Send("ℹ️ 2FA is already enabled on your account.")
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		log.Printf("❌ Failed to generate TOTP secret: %v", err)
+		return c.Send("❌ Failed to generate a 2FA secret. Try again.")
+	}
+
+	admin.TOTPSecret = secret
+	db.Save(&admin)
+
+	label := c.Sender().Username
+	if label == "" {
+		label = strconv.FormatInt(c.Sender().ID, 10)
+	}
+	uri := fmt.Sprintf("otpauth://totp/KeepAliveBot:%s?secret=%s&issuer=KeepAliveBot", label, secret)
+
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		log.Printf("❌ Failed to render 2FA QR code: %v", err)
+		return c.Send(fmt.Sprintf("✅ 2FA secret: %s\nAdd it to your authenticator app, then confirm with /verify2fa <code>.", secret))
+	}
+
+	photo := &tele.Photo{
+		File:    tele.FromReader(bytes.NewReader(png)),
+		Caption: fmt.Sprintf("🔐 Scan this in your authenticator app, then confirm with /verify2fa <code>.\n\nSecret: %s", secret),
+	}
+	return c.Send(photo)
+}
+
+// Handler: /verify2fa
+func handleVerify2FA(c tele.Context) error {
+	if !isAdmin(c.Sender().ID) {
+		return c.Send("❌ Unauthorized.")
+	}
+
+	args := strings.Fields(c.Text())
+	if len(args) < 2 {
+		return c.Send("❌ Usage: /verify2fa <code>")
+	}
+
+	var admin Admin
+	if err := db.Where("telegram_id = ?", c.Sender().ID).First(&admin).Error; err != nil || admin.TOTPSecret == "" {
+		return c.Send("❌ Run /enable2fa first.")
+	}
+	if admin.TOTPEnabled {
+		return c.Send("ℹ️ 2FA is already enabled on your account.")
+	}
+
+	if !verifyTOTP(admin.TOTPSecret, args[1]) {
+		return c.Send("❌ Invalid code. Try again.")
+	}
+
+	admin.TOTPEnabled = true
+	db.Save(&admin)
+	markAuthenticated(c.Sender().ID)
+
+	return c.Send("✅ 2FA enabled! You have an authenticated session for the next 5 minutes.")
+}
+
+// Handler: /auth
+func handleAuth(c tele.Context) error {
+	if !isAdmin(c.Sender().ID) {
+		return c.Send("❌ Unauthorized.")
+	}
+
+	args := strings.Fields(c.Text())
+	if len(args) < 2 {
+		return c.Send("❌ Usage: /auth <code>")
+	}
+
+	var admin Admin
+	if err := db.Where("telegram_id = ?", c.Sender().ID).First(&admin).Error; err != nil || !admin.TOTPEnabled {
+		return c.Send("❌ 2FA is not enabled on your account. Run /enable2fa first.")
+	}
+
+	if !verifyTOTP(admin.TOTPSecret, args[1]) {
+		return c.Send("❌ Invalid code.")
+	}
+
+	markAuthenticated(c.Sender().ID)
+	return c.Send("✅ Authenticated for the next 5 minutes.")
+}
+
+// generateTOTPSecret returns a random 20-byte base32-encoded TOTP secret.
+func generateTOTPSecret() (string, error) {
+	raw := make([]byte, 20)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw), nil
+}
+
+// verifyTOTP checks code against the RFC 6238 TOTP value for secret,
+// allowing a ±1 step (±30s) window to tolerate clock skew.
+func verifyTOTP(secret, code string) bool {
+	now := time.Now()
+	for _, step := range []int{-1, 0, 1} {
+		t := now.Add(time.Duration(step) * 30 * time.Second)
+		expected, err := totpAt(secret, t)
+		if err == nil && expected == code {
+			return true
+		}
+	}
+	return false
+}
+
+// totpAt computes the RFC 6238 TOTP value for secret at time t: HMAC-SHA1
+// over the 8-byte big-endian counter (unixTime/30), dynamic truncation, and
+// reduction mod 10^6.
+func totpAt(secret string, t time.Time) (string, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	counter := uint64(t.Unix() / 30)
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(buf)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	truncated := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", truncated%1000000), nil
+}
+
+// Scheduler: Send keep-alive pings. cronScheduler itself is created
+// synchronously in main() before the bot starts polling; this just loads
+// existing bots and starts the cron loop.
 func startScheduler() {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
+	var bots []Bot
+	db.Find(&bots)
+	for _, b := range bots {
+		scheduleBot(b)
+	}
 
+	cronScheduler.Start()
 	log.Println("✅ Scheduler started")
+}
 
-	for range ticker.C {
-		var bots []Bot
-		db.Find(&bots)
+// cronParser validates and parses Schedule strings the same way
+// cronScheduler does: standard 5-field cron plus @every/@hourly-style
+// descriptors.
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
 
-		for _, b := range bots {
-			if time.Since(b.LastPing).Minutes() >= float64(b.IntervalMinutes) {
-				go sendKeepAlivePing(b)
-			}
+// normalizeSchedule turns the underscore-joined form used in chat commands
+// (e.g. "*/5_*_*_*_*") back into a real cron expression.
+func normalizeSchedule(expr string) string {
+	return strings.ReplaceAll(expr, "_", " ")
+}
+
+// validateSchedule reports whether expr parses as a valid cron expression.
+func validateSchedule(expr string) error {
+	_, err := cronParser.Parse(expr)
+	return err
+}
+
+// scheduleBot (re)registers a bot's cron entry under cronEntries, replacing
+// any existing entry, so /addbot and /schedule can take effect immediately.
+func scheduleBot(b Bot) {
+	unscheduleBot(b.ID)
+
+	entryID, err := cronScheduler.AddFunc(b.Schedule, func() {
+		var current Bot
+		if err := db.First(&current, b.ID).Error; err != nil {
+			return
 		}
+		go sendKeepAlivePing(current)
+	})
+	if err != nil {
+		log.Printf("❌ Failed to schedule @%s (%q): %v", b.BotUsername, b.Schedule, err)
+		return
 	}
+
+	cronEntriesMu.Lock()
+	cronEntries[b.ID] = entryID
+	cronEntriesMu.Unlock()
 }
 
-// Send keep-alive ping to bot
-func sendKeepAlivePing(b Bot) {
-	log.Printf("📡 Pinging @%s at %s...", b.BotUsername, b.BotURL)
+// unscheduleBot removes a bot's cron entry, if one exists.
+func unscheduleBot(botID uint) {
+	cronEntriesMu.Lock()
+	defer cronEntriesMu.Unlock()
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	if entryID, ok := cronEntries[botID]; ok {
+		cronScheduler.Remove(entryID)
+		delete(cronEntries, botID)
 	}
+}
+
+// nextRun returns the next scheduled run time for a bot, or the zero Time if
+// it has no active cron entry.
+func nextRun(botID uint) time.Time {
+	cronEntriesMu.Lock()
+	entryID, ok := cronEntries[botID]
+	cronEntriesMu.Unlock()
+
+	if !ok {
+		return time.Time{}
+	}
+	return cronScheduler.Entry(entryID).Next
+}
 
-	// Try to ping the bot's URL
+// Checker performs a single liveness check against a bot and reports
+// whether it succeeded, how long it took, and an error message on failure.
+type Checker interface {
+	Check(b Bot) (success bool, latency time.Duration, errMsg string)
+}
+
+// newChecker returns the Checker implementation for a bot's CheckType,
+// falling back to the http checker for an unrecognized or empty type.
+func newChecker(checkType string) Checker {
+	switch checkType {
+	case "tcp":
+		return tcpChecker{}
+	case "telegram":
+		return telegramChecker{}
+	case "json":
+		return jsonChecker{}
+	default:
+		return httpChecker{}
+	}
+}
+
+// checkTarget returns the human-readable target a bot is checked against,
+// regardless of which field the check type stores it in.
+func checkTarget(b Bot) string {
+	if b.CheckType == "telegram" {
+		return b.BotToken
+	}
+	return b.BotURL
+}
+
+// httpChecker treats a 2xx or 3xx response from BotURL as success.
+type httpChecker struct{}
+
+func (httpChecker) Check(b Bot) (bool, time.Duration, string) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	start := time.Now()
 	resp, err := client.Get(b.BotURL)
-	
-	success := false
-	status := "Offline"
+	latency := time.Since(start)
+	if err != nil {
+		return false, latency, err.Error()
+	}
+	defer resp.Body.Close()
 
-	if err == nil {
-		defer resp.Body.Close()
-		// Consider 2xx and 3xx as success
-		if resp.StatusCode >= 200 && resp.StatusCode < 400 {
-			success = true
-			status = "Online"
-			log.Printf("✅ Successfully pinged @%s (Status: %d)", b.BotUsername, resp.StatusCode)
-		} else {
-			log.Printf("⚠️ Bot @%s responded with status %d", b.BotUsername, resp.StatusCode)
+	if resp.StatusCode >= 200 && resp.StatusCode < 400 {
+		return true, latency, ""
+	}
+	return false, latency, fmt.Sprintf("unexpected status %d", resp.StatusCode)
+}
+
+// tcpChecker treats a successful TCP dial to BotURL ("host:port") as success.
+type tcpChecker struct{}
+
+func (tcpChecker) Check(b Bot) (bool, time.Duration, string) {
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", b.BotURL, 10*time.Second)
+	latency := time.Since(start)
+	if err != nil {
+		return false, latency, err.Error()
+	}
+	conn.Close()
+	return true, latency, ""
+}
+
+// telegramChecker calls the Telegram Bot API getMe endpoint with BotToken
+// and treats ok:true in the response as success.
+type telegramChecker struct{}
+
+func (telegramChecker) Check(b Bot) (bool, time.Duration, string) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	start := time.Now()
+	resp, err := client.Get(fmt.Sprintf("https://api.telegram.org/bot%s/getMe", b.BotToken))
+	latency := time.Since(start)
+	if err != nil {
+		return false, latency, err.Error()
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		OK bool `json:"ok"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return false, latency, fmt.Sprintf("invalid response: %v", err)
+	}
+	if !payload.OK {
+		return false, latency, "getMe returned ok:false"
+	}
+	return true, latency, ""
+}
+
+// jsonChecker performs a GET against BotURL and asserts SuccessExpr, a
+// JSONPath-style expression such as `$.status == "ok"`, against the
+// decoded response body.
+type jsonChecker struct{}
+
+func (jsonChecker) Check(b Bot) (bool, time.Duration, string) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	start := time.Now()
+	resp, err := client.Get(b.BotURL)
+	latency := time.Since(start)
+	if err != nil {
+		return false, latency, err.Error()
+	}
+	defer resp.Body.Close()
+
+	var doc interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return false, latency, fmt.Sprintf("invalid JSON response: %v", err)
+	}
+
+	ok, err := evalJSONExpr(doc, b.SuccessExpr)
+	if err != nil {
+		return false, latency, err.Error()
+	}
+	if !ok {
+		return false, latency, fmt.Sprintf("assertion failed: %s", b.SuccessExpr)
+	}
+	return true, latency, ""
+}
+
+// evalJSONExpr evaluates a minimal JSONPath-style assertion of the form
+// "$.field.nested == value" against a decoded JSON document, where value is
+// a quoted string, a number, or true/false.
+func evalJSONExpr(doc interface{}, expr string) (bool, error) {
+	parts := strings.SplitN(expr, "==", 2)
+	if len(parts) != 2 {
+		return false, fmt.Errorf("invalid expression %q: expected \"$.path == value\"", expr)
+	}
+
+	got, err := resolveJSONPath(doc, strings.TrimSpace(parts[0]))
+	if err != nil {
+		return false, err
+	}
+
+	want := parseJSONLiteral(strings.TrimSpace(parts[1]))
+
+	return fmt.Sprintf("%v", got) == fmt.Sprintf("%v", want), nil
+}
+
+// resolveJSONPath walks a dotted path like "$.status" or "status.code"
+// through a decoded JSON document and returns the value found there.
+func resolveJSONPath(doc interface{}, path string) (interface{}, error) {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return doc, nil
+	}
+
+	cur := doc
+	for _, segment := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot index %q: not an object", segment)
 		}
+		val, ok := m[segment]
+		if !ok {
+			return nil, fmt.Errorf("field %q not found", segment)
+		}
+		cur = val
+	}
+	return cur, nil
+}
+
+// parseJSONLiteral parses a quoted string, boolean, or number literal from a
+// JSONPath-style expression's right-hand side.
+func parseJSONLiteral(s string) interface{} {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	if s == "true" || s == "false" {
+		return s == "true"
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+// Send keep-alive ping to bot
+func sendKeepAlivePing(b Bot) {
+	log.Printf("📡 Checking @%s (%s) at %s...", b.BotUsername, b.CheckType, checkTarget(b))
+
+	checker := newChecker(b.CheckType)
+	success, latency, errMsg := checker.Check(b)
+
+	status := "Offline"
+	if success {
+		status = "Online"
+		log.Printf("✅ @%s is up (%s, %dms)", b.BotUsername, b.CheckType, latency.Milliseconds())
 	} else {
-		log.Printf("❌ Failed to ping @%s: %v", b.BotUsername, err)
+		log.Printf("❌ @%s check failed: %s", b.BotUsername, errMsg)
 	}
 
 	// Update bot status and last ping time
@@ -403,10 +1482,14 @@ func sendKeepAlivePing(b Bot) {
 
 	// Log the ping result
 	db.Create(&UptimeLog{
-		BotID:  b.ID,
-		Result: success,
+		BotID:     b.ID,
+		Result:    success,
+		LatencyMs: latency.Milliseconds(),
+		Error:     errMsg,
 	})
 
+	metrics.Observe(b.BotUsername, success, latency, time.Now())
+
 	// Alert admin if bot goes offline
 	if !success {
 		notifyAdminOffline(b)
@@ -420,8 +1503,8 @@ func notifyAdminOffline(b Bot) {
 	
 	if admin.TelegramID != 0 {
 		recipient := &tele.User{ID: admin.TelegramID}
-		message := fmt.Sprintf("⚠️ Alert: Bot @%s is OFFLINE!\n\nURL: %s\nLast successful ping: %s",
-			b.BotUsername, b.BotURL, b.LastPing.Format("02 Jan 2006 15:04"))
+		message := fmt.Sprintf("⚠️ Alert: Bot @%s is OFFLINE!\n\nTarget: %s\nLast successful ping: %s",
+			b.BotUsername, checkTarget(b), b.LastPing.Format("02 Jan 2006 15:04"))
 		bot.Send(recipient, message)
 	}
 }
@@ -443,8 +1526,138 @@ func startHTTPServer() {
 		fmt.Fprint(w, "OK")
 	})
 
+	http.Handle("/metrics", metrics.Handler())
+	http.HandleFunc("/dashboard", handleDashboard)
+	http.HandleFunc("/api/uptime", handleUptimeAPI)
+
 	log.Printf("🌐 HTTP server starting on port %s...", port)
 	if err := http.ListenAndServe(":"+port, nil); err != nil {
 		log.Fatal("HTTP server failed:", err)
 	}
 }
+
+// dashboardHTML is a self-contained Grafana-style page (Chart.js from CDN)
+// that plots /api/uptime data for whichever bot ID the operator enters.
+const dashboardHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Keep-Alive Bot Dashboard</title>
+  <script src="https://cdn.jsdelivr.net/npm/chart.js"></script>
+</head>
+<body>
+  <h1>Bot Uptime</h1>
+  <label>Bot ID: <input id="botId" type="number" value="1"></label>
+  <label>Window: <input id="window" type="text" value="24h"></label>
+  <button onclick="loadChart()">Load</button>
+  <canvas id="uptimeChart" width="800" height="400"></canvas>
+  <script>
+    let chart;
+    async function loadChart() {
+      const bot = document.getElementById('botId').value;
+      const win = document.getElementById('window').value;
+      const res = await fetch('/api/uptime?bot=' + bot + '&window=' + win);
+      const points = await res.json();
+      const labels = points.map(p => new Date(p.timestamp * 1000).toLocaleString());
+      const uptime = points.map(p => p.uptime_pct);
+      const latency = points.map(p => p.avg_latency_ms);
+
+      if (chart) chart.destroy();
+      chart = new Chart(document.getElementById('uptimeChart'), {
+        type: 'line',
+        data: {
+          labels: labels,
+          datasets: [
+            { label: 'Uptime %', data: uptime, yAxisID: 'y' },
+            { label: 'Avg Latency (ms)', data: latency, yAxisID: 'y1' },
+          ],
+        },
+        options: {
+          scales: {
+            y: { type: 'linear', position: 'left', min: 0, max: 100 },
+            y1: { type: 'linear', position: 'right', grid: { drawOnChartArea: false } },
+          },
+        },
+      });
+    }
+    loadChart();
+  </script>
+</body>
+</html>`
+
+// Handler: GET /dashboard
+func handleDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, dashboardHTML)
+}
+
+// uptimePoint is one 5-minute bucket of aggregated UptimeLog data returned
+// by /api/uptime.
+type uptimePoint struct {
+	Timestamp    int64   `json:"timestamp"`
+	UptimePct    float64 `json:"uptime_pct"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+}
+
+// Handler: GET /api/uptime?bot=<id>&window=24h
+// Aggregates a bot's UptimeLog rows over the given window into 5-minute
+// buckets for the /dashboard chart.
+func handleUptimeAPI(w http.ResponseWriter, r *http.Request) {
+	botID, err := strconv.ParseUint(r.URL.Query().Get("bot"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid or missing bot id", http.StatusBadRequest)
+		return
+	}
+
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = "24h"
+	}
+	lookback, err := time.ParseDuration(window)
+	if err != nil {
+		http.Error(w, "invalid window", http.StatusBadRequest)
+		return
+	}
+
+	var logs []UptimeLog
+	db.Where("bot_id = ? AND timestamp >= ?", uint(botID), time.Now().Add(-lookback)).
+		Order("timestamp").Find(&logs)
+
+	const bucketSize = 5 * time.Minute
+	type bucket struct {
+		total, success int
+		latencySum     int64
+	}
+	buckets := make(map[int64]*bucket)
+	var order []int64
+
+	for _, l := range logs {
+		key := l.Timestamp.Truncate(bucketSize).Unix()
+		b, ok := buckets[key]
+		if !ok {
+			b = &bucket{}
+			buckets[key] = b
+			order = append(order, key)
+		}
+		b.total++
+		if l.Result {
+			b.success++
+		}
+		b.latencySum += l.LatencyMs
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+
+	points := make([]uptimePoint, 0, len(order))
+	for _, key := range order {
+		b := buckets[key]
+		point := uptimePoint{Timestamp: key}
+		if b.total > 0 {
+			point.UptimePct = (float64(b.success) / float64(b.total)) * 100
+			point.AvgLatencyMs = float64(b.latencySum) / float64(b.total)
+		}
+		points = append(points, point)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(points)
+}
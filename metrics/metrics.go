@@ -0,0 +1,61 @@
+// Package metrics exposes Prometheus collectors for the keep-alive bot so
+// operators can wire it into an existing observability stack instead of
+// relying solely on Telegram alerts.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	PingTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "keepalive_ping_total",
+		Help: "Total number of keep-alive pings sent, labeled by bot and result.",
+	}, []string{"bot", "result"})
+
+	PingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "keepalive_ping_duration_seconds",
+		Help:    "Keep-alive ping round-trip duration in seconds, labeled by bot.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"bot"})
+
+	BotUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "keepalive_bot_up",
+		Help: "Whether a monitored bot's most recent check succeeded (1) or not (0).",
+	}, []string{"bot"})
+
+	BotLastPingTimestamp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "keepalive_bot_last_ping_timestamp",
+		Help: "Unix timestamp of a monitored bot's most recent check.",
+	}, []string{"bot"})
+)
+
+// Observe records the outcome of a single keep-alive check against all four
+// collectors: the pass/fail counter, the latency histogram, the up/down
+// gauge, and the last-ping timestamp gauge.
+func Observe(bot string, success bool, latency time.Duration, at time.Time) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	PingTotal.WithLabelValues(bot, result).Inc()
+	PingDuration.WithLabelValues(bot).Observe(latency.Seconds())
+
+	up := 0.0
+	if success {
+		up = 1.0
+	}
+	BotUp.WithLabelValues(bot).Set(up)
+	BotLastPingTimestamp.WithLabelValues(bot).Set(float64(at.Unix()))
+}
+
+// Handler returns the HTTP handler that exposes all registered collectors
+// in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}